@@ -0,0 +1,157 @@
+package opt
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Format identifies the on-disk encoding of a config file passed to
+// [LoadConfig].
+type Format int
+
+const (
+	// FormatYAML parses the config file as YAML.
+	FormatYAML Format = iota
+	// FormatTOML parses the config file as TOML.
+	FormatTOML
+	// FormatJSON parses the config file as JSON.
+	FormatJSON
+)
+
+var (
+	configsMu sync.Mutex
+	configs   = map[*cobra.Command]map[string]string{}
+)
+
+// LoadConfig reads the file at path, decodes it using format, and
+// makes its values available as a config-file source for every
+// option registered against the root of cmd's command tree or any
+// of its subcommands.
+//
+// LoadConfig must be called before cmd.Execute, so the values are
+// available by the time cobra.OnInitialize runs. Values are keyed
+// by the option's name (see [ConfigKey] to override), scoped by the
+// names of any subcommands between that root and the option's command
+// (e.g. "serve.port" configures the "port" option registered on the
+// "serve" subcommand).
+func LoadConfig(cmd *cobra.Command, path string, format Format) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("opt: reading config file %q: %w", path, err)
+	}
+
+	var raw map[string]any
+	switch format {
+	case FormatYAML:
+		err = yaml.Unmarshal(b, &raw)
+	case FormatTOML:
+		_, err = toml.Decode(string(b), &raw)
+	case FormatJSON:
+		err = json.Unmarshal(b, &raw)
+	default:
+		return fmt.Errorf("opt: unknown config format %v", format)
+	}
+	if err != nil {
+		return fmt.Errorf("opt: parsing config file %q: %w", path, err)
+	}
+
+	flat := map[string]string{}
+	flattenConfig("", raw, flat)
+
+	configsMu.Lock()
+	defer configsMu.Unlock()
+	configs[configRoot(cmd)] = flat
+
+	return nil
+}
+
+// flattenConfig walks a decoded config document, recording a dotted
+// key for every leaf that is not a map, formatted with fmt.Sprint
+// (so a list is recorded whole), e.g. {"serve": {"port": 8080}}
+// becomes {"serve.port": "8080"}.
+func flattenConfig(prefix string, v any, out map[string]string) {
+	switch vv := v.(type) {
+	case map[string]any:
+		for k, child := range vv {
+			flattenConfig(dotJoin(prefix, k), child, out)
+		}
+	case map[any]any:
+		for k, child := range vv {
+			flattenConfig(dotJoin(prefix, fmt.Sprint(k)), child, out)
+		}
+	default:
+		out[prefix] = fmt.Sprint(vv)
+	}
+}
+
+func dotJoin(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// configLoaded reports whether a config file has been loaded for
+// cmd's command tree.
+func configLoaded(cmd *cobra.Command) bool {
+	configsMu.Lock()
+	defer configsMu.Unlock()
+
+	_, ok := configs[configRoot(cmd)]
+	return ok
+}
+
+// configValue looks up key in the config file loaded for cmd's
+// command tree, if any.
+func configValue(cmd *cobra.Command, key string) (string, bool) {
+	configsMu.Lock()
+	defer configsMu.Unlock()
+
+	flat, ok := configs[configRoot(cmd)]
+	if !ok {
+		return "", false
+	}
+
+	v, ok := flat[key]
+	return v, ok
+}
+
+// configRoot walks up to the command that LoadConfig was (or would
+// be) called with, i.e. the root of cmd's command tree.
+func configRoot(cmd *cobra.Command) *cobra.Command {
+	root := cmd
+	for root.HasParent() {
+		root = root.Parent()
+	}
+	return root
+}
+
+// configKeyFor computes the dotted config key for an option named
+// name registered against cmd, prefixing it with the names of any
+// subcommands between cmd and its root. An explicit configKey (from
+// [ConfigKey]) is used verbatim, without subcommand scoping, since
+// it overrides the looked-up key entirely.
+func configKeyFor(cmd *cobra.Command, name, configKey string) string {
+	if configKey != "" {
+		return configKey
+	}
+
+	key := name
+
+	var parts []string
+	for c := cmd; c != nil && c.HasParent(); c = c.Parent() {
+		parts = append([]string{c.Name()}, parts...)
+	}
+	if len(parts) > 0 {
+		key = strings.Join(parts, ".") + "." + key
+	}
+
+	return key
+}
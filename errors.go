@@ -0,0 +1,64 @@
+package opt
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	errsMu        sync.Mutex
+	errs          = map[*cobra.Command][]error{}
+	validateHooks = map[*cobra.Command]bool{}
+)
+
+// addErr records err against cmd's aggregated startup errors, to be
+// returned later by [Validate].
+func addErr(cmd *cobra.Command, err error) {
+	errsMu.Lock()
+	defer errsMu.Unlock()
+
+	errs[cmd] = append(errs[cmd], err)
+}
+
+// Validate returns the aggregated errors encountered while
+// resolving cmd's options - environment variable or config file
+// values that failed to parse, and any failures from [Validator]
+// functions - or nil if there were none.
+//
+// A PersistentPreRunE that calls Validate is installed automatically
+// for every command used with this package, chained after any
+// PersistentPreRunE already set on it, so callers do not normally
+// need to call Validate themselves.
+func Validate(cmd *cobra.Command) error {
+	errsMu.Lock()
+	cmdErrs := append([]error(nil), errs[cmd]...)
+	errsMu.Unlock()
+
+	return errors.Join(cmdErrs...)
+}
+
+// installValidateHook wires cmd's PersistentPreRunE (chained after
+// any existing one) to surface cmd's aggregated startup errors
+// before RunE executes. It is a no-op after the first call for a
+// given cmd.
+func installValidateHook(cmd *cobra.Command) {
+	errsMu.Lock()
+	defer errsMu.Unlock()
+
+	if validateHooks[cmd] {
+		return
+	}
+	validateHooks[cmd] = true
+
+	prev := cmd.PersistentPreRunE
+	cmd.PersistentPreRunE = func(c *cobra.Command, args []string) error {
+		if prev != nil {
+			if err := prev(c, args); err != nil {
+				return err
+			}
+		}
+		return Validate(cmd)
+	}
+}
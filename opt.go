@@ -5,8 +5,9 @@
 //
 // By default, all options can be specified as:
 //  1. Default values (lowest precedent)
-//  2. Environment variables
-//  3. Command-line flags (highest precedent)
+//  2. Config file values, once one is loaded with [LoadConfig] (see below)
+//  3. Environment variables
+//  4. Command-line flags (highest precedent)
 //
 // All commands have a name, which is used to set default
 // values.
@@ -23,9 +24,17 @@
 // to the persistent [cobra.FlagSet] instead. To disallow
 // an option from being specified as a command-line flag,
 // override its flag name to be "" using [FlagName].
+//
+// Options can also be sourced from a config file loaded with
+// [LoadConfig]. The default config key is the option's name,
+// scoped by the names of any subcommands between the root command
+// and this option's command (e.g. "serve.port" configures
+// the "port" option registered on the "serve" subcommand). Use
+// [ConfigKey] to override it.
 package opt
 
 import (
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -35,9 +44,12 @@ import (
 	"github.com/spf13/pflag"
 )
 
-type OptType interface {
-	string | bool
-}
+// OptType is the generic constraint satisfied by every option
+// value. It used to be a closed union of the types this package has
+// a dedicated constructor for; it is now an alias for [any] so that
+// [Var] can be used with arbitrary types too (e.g. ones backed by a
+// caller's own [pflag.Value]).
+type OptType = any
 
 type opt[T OptType] struct {
 	once             sync.Once
@@ -53,14 +65,22 @@ type opt[T OptType] struct {
 	flagIsDir        bool
 	flagIsFile       bool
 	envParseFunc     func(string) (T, error)
+	flagCreateFunc   func(flagSet *pflag.FlagSet, o *opt[T])
 	defaultValue     T
 	v                *T
+	configKey        string
+	configFilePath   string
+	configFormat     Format
+	required         bool
+	source           Source
+	validators       []func(T) error
 }
 
 func (o *opt[T]) init() {
 	o.once.Do(func() {
 		if o.flagName != "" && o.flag.Changed {
 			*o.v = o.flagV
+			o.source = SourceFlag
 			return
 		}
 
@@ -68,15 +88,35 @@ func (o *opt[T]) init() {
 			if strV, ok := os.LookupEnv(o.envName); ok {
 				envV, err := o.envParseFunc(strV)
 				if err != nil {
-					panic(err)
+					addErr(o.cmd, fmt.Errorf("option %q: env %s=%q: %w", o.name, o.envName, strV, err))
+					return
 				}
 				*o.v = envV
+				o.source = SourceEnv
+				return
+			}
+		}
+
+		if strV, ok := configValue(o.cmd, configKeyFor(o.cmd, o.name, o.configKey)); ok {
+			cfgV, err := o.envParseFunc(strV)
+			if err != nil {
+				addErr(o.cmd, fmt.Errorf("option %q: config value %q: %w", o.name, strV, err))
 				return
 			}
+			*o.v = cfgV
+			o.source = SourceConfig
+			return
 		}
 
 		*o.v = o.defaultValue
+		o.source = SourceDefault
 	})
+
+	for _, fn := range o.validators {
+		if err := fn(*o.v); err != nil {
+			addErr(o.cmd, fmt.Errorf("option %q: %w", o.name, err))
+		}
+	}
 }
 
 // String sets up a string option with the specified name
@@ -107,18 +147,43 @@ func Bool(cmd *cobra.Command, v *bool, name string, optFuncs ...OptFunc[bool]) {
 	)
 }
 
+// Var sets up an option of a type not covered by this package's
+// other constructors, for arbitrary types backed by a caller's own
+// [pflag.Value] implementation.
+//
+// envParseFunc parses the option's string form (used for both
+// environment variables and config file values). flagCreateFunc is
+// called to register the flag against flagSet; it is given
+// flagVar (which it should wrap in a [pflag.Value] and pass to
+// flagSet.VarP, or an equivalent XxxVarP call of the caller's own
+// type), the flag's name, shorthand, default value, and usage
+// string.
+func Var[T any](cmd *cobra.Command, v *T, name string, envParseFunc func(string) (T, error), flagCreateFunc func(flagSet *pflag.FlagSet, flagVar *T, flagName, flagShorthand string, defaultValue T, usage string), optFuncs ...OptFunc[T]) {
+	doVar(
+		cmd,
+		v,
+		name,
+		optFuncs,
+		envParseFunc,
+		func(flagSet *pflag.FlagSet, o *opt[T]) {
+			flagCreateFunc(flagSet, &o.flagV, o.flagName, o.flagShorthand, o.defaultValue, o.usage)
+		},
+	)
+}
+
 func doVar[T OptType](cmd *cobra.Command, v *T, name string, optFuncs []OptFunc[T], envParseFunc func(in string) (out T, err error), flagCreateFunc func(flagSet *pflag.FlagSet, o *opt[T])) {
 	if v == nil {
 		panic("opt: v cannot be nil")
 	}
 
 	ret := opt[T]{
-		cmd:          cmd,
-		name:         name,
-		flagName:     name,
-		envName:      strings.ToUpper(name),
-		v:            v,
-		envParseFunc: envParseFunc,
+		cmd:            cmd,
+		name:           name,
+		flagName:       name,
+		envName:        strings.ToUpper(name),
+		v:              v,
+		envParseFunc:   envParseFunc,
+		flagCreateFunc: flagCreateFunc,
 	}
 
 	for _, opt := range optFuncs {
@@ -146,6 +211,19 @@ func doVar[T OptType](cmd *cobra.Command, v *T, name string, optFuncs []OptFunc[
 		}
 	}
 
+	if ret.configFilePath != "" {
+		if !configLoaded(cmd) {
+			if err := LoadConfig(cmd, ret.configFilePath, ret.configFormat); err != nil {
+				addErr(cmd, fmt.Errorf("option %q: %w", ret.name, err))
+			}
+		}
+	}
+
+	registerRequired(cmd, &ret)
+	registerSourced(cmd, &ret)
+	registerResettable(cmd, &ret)
+	installValidateHook(cmd)
+
 	cobra.OnInitialize(ret.init)
 }
 
@@ -221,3 +299,61 @@ func Default[T OptType](v T) OptFunc[T] {
 		o.defaultValue = v
 	}
 }
+
+// ConfigKey overrides the key used to look up this option's value
+// in a config file loaded with [LoadConfig].
+//
+// As stated in the package documentation, if the config key is not
+// overridden, it defaults to the option's name, scoped by any
+// intervening subcommand names.
+func ConfigKey[T OptType](configKey string) OptFunc[T] {
+	return func(o *opt[T]) {
+		o.configKey = configKey
+	}
+}
+
+// ConfigFile is shorthand for calling [LoadConfig] with this
+// option's [cobra.Command] once it is registered, using a path
+// fixed at registration time. It is a convenience for the common
+// case where a command always reads its config from a known
+// location (e.g. "/etc/myapp/config.yaml"); it cannot be used to
+// let a flag's own parsed value (e.g. a "--config" flag) name the
+// file, since flags aren't parsed yet when options are registered.
+// For that, call [LoadConfig] yourself once the flag is parsed,
+// from a function passed to [cobra.OnInitialize] before any option
+// is registered: cobra runs those functions after parsing flags and
+// before any PersistentPreRunE, in the order they were added, and
+// each option reads its config value in its own such function.
+//
+// If a config file has already been loaded for this option's
+// command, ConfigFile does nothing.
+//
+// The format defaults to [FormatYAML]; use [ConfigFormat] to
+// override it.
+func ConfigFile[T OptType](path string) OptFunc[T] {
+	return func(o *opt[T]) {
+		o.configFilePath = path
+	}
+}
+
+// ConfigFormat sets the format used to parse the file named by
+// [ConfigFile]. It has no effect without [ConfigFile].
+func ConfigFormat[T OptType](format Format) OptFunc[T] {
+	return func(o *opt[T]) {
+		o.configFormat = format
+	}
+}
+
+// Validator registers a semantic validation function for this
+// option (e.g. a range check, enum membership, or file-exists
+// check). It runs once the option's value has been resolved from
+// its flag, environment variable, config file, or default, and any
+// error it returns is surfaced by [Validate] (and, automatically,
+// by the command's PersistentPreRunE).
+//
+// Multiple validators may be registered; all of them run.
+func Validator[T OptType](fn func(T) error) OptFunc[T] {
+	return func(o *opt[T]) {
+		o.validators = append(o.validators, fn)
+	}
+}
@@ -0,0 +1,119 @@
+package opt_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/a-jentleman/opt"
+	"github.com/a-jentleman/opt/opttest"
+	"github.com/spf13/cobra"
+)
+
+func newTestCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:  "test",
+		RunE: func(cmd *cobra.Command, args []string) error { return nil },
+	}
+}
+
+func TestPrecedence(t *testing.T) {
+	var v string
+	cmd := newTestCmd()
+	opt.String(cmd, &v, "name", opt.Default("default-value"), opt.EnvName[string]("TEST_NAME"))
+
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want string
+	}{
+		{name: "default", want: "default-value"},
+		{name: "env overrides default", env: map[string]string{"TEST_NAME": "env-value"}, want: "env-value"},
+		{name: "flag overrides env", args: []string{"--name", "flag-value"}, env: map[string]string{"TEST_NAME": "env-value"}, want: "flag-value"},
+		{name: "flag overrides default", args: []string{"--name", "flag-value"}, want: "flag-value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := opttest.RunWithArgs(cmd, tt.args, tt.env); err != nil {
+				t.Fatalf("RunWithArgs() error = %v", err)
+			}
+			if v != tt.want {
+				t.Errorf("got %q, want %q", v, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequired(t *testing.T) {
+	var v string
+	cmd := newTestCmd()
+	opt.String(cmd, &v, "name", opt.Required[string](), opt.EnvName[string]("TEST_REQUIRED_NAME"))
+
+	if err := opttest.RunWithArgs(cmd, nil, nil); err == nil {
+		t.Fatal("RunWithArgs() error = nil, want error for missing required option")
+	}
+
+	if err := opttest.RunWithArgs(cmd, []string{"--name", "value"}, nil); err != nil {
+		t.Fatalf("RunWithArgs() error = %v", err)
+	}
+	if v != "value" {
+		t.Errorf("got %q, want %q", v, "value")
+	}
+}
+
+func TestValidator(t *testing.T) {
+	var v int
+	cmd := newTestCmd()
+	opt.Int(cmd, &v, "port", opt.Validator(func(v int) error {
+		if v < 1 || v > 65535 {
+			return errors.New("port out of range")
+		}
+		return nil
+	}))
+
+	if err := opttest.RunWithArgs(cmd, []string{"--port", "99999"}, nil); err == nil {
+		t.Fatal("RunWithArgs() error = nil, want error for out-of-range port")
+	}
+
+	if err := opttest.RunWithArgs(cmd, []string{"--port", "8080"}, nil); err != nil {
+		t.Fatalf("RunWithArgs() error = %v", err)
+	}
+	if v != 8080 {
+		t.Errorf("got %d, want %d", v, 8080)
+	}
+}
+
+func TestSliceAndMapReset(t *testing.T) {
+	var tags []string
+	var labels map[string]string
+	cmd := newTestCmd()
+	opt.StringSlice(cmd, &tags, "tags", opt.Default([]string{"d1", "d2"}))
+	opt.StringToStringMap(cmd, &labels, "labels", opt.Default(map[string]string{"d": "1"}))
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantTags   []string
+		wantLabels map[string]string
+	}{
+		{name: "defaults", wantTags: []string{"d1", "d2"}, wantLabels: map[string]string{"d": "1"}},
+		{name: "flags replace defaults", args: []string{"--tags", "x,y,z", "--labels", "a=1,b=2"}, wantTags: []string{"x", "y", "z"}, wantLabels: map[string]string{"a": "1", "b": "2"}},
+		{name: "back to defaults", wantTags: []string{"d1", "d2"}, wantLabels: map[string]string{"d": "1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := opttest.RunWithArgs(cmd, tt.args, nil); err != nil {
+				t.Fatalf("RunWithArgs() error = %v", err)
+			}
+			if !reflect.DeepEqual(tags, tt.wantTags) {
+				t.Errorf("tags = %v, want %v", tags, tt.wantTags)
+			}
+			if !reflect.DeepEqual(labels, tt.wantLabels) {
+				t.Errorf("labels = %v, want %v", labels, tt.wantLabels)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Package opttest provides test helpers for exercising [cobra.Command]s
+// built with [opt], letting the same command be executed repeatedly
+// with different arguments and environment variables.
+//
+// Without this package, tests cannot re-invoke a command with
+// different inputs: opt relies on [cobra.OnInitialize] and
+// [sync.Once] to resolve each option's value exactly once per
+// process.
+package opttest
+
+import (
+	"os"
+
+	"github.com/a-jentleman/opt"
+	"github.com/spf13/cobra"
+)
+
+// RunWithArgs resets cmd (see [Reset]), sets args and env for the
+// duration of the call, and executes cmd, returning any error from
+// execution. It is safe to call repeatedly against the same cmd,
+// e.g. from a table-driven test.
+func RunWithArgs(cmd *cobra.Command, args []string, env map[string]string) error {
+	Reset(cmd)
+
+	restoreArgs := setArgs(cmd, args)
+	defer restoreArgs()
+
+	restoreEnv := setEnv(env)
+	defer restoreEnv()
+
+	return cmd.Execute()
+}
+
+// Reset undoes the effects of a previous Execute on cmd: it resets
+// every opt-registered option's flag to its unset, default value
+// and re-arms its internal state, via [opt.Reset]. This makes it
+// safe to execute cmd again with different flags, env, or config.
+func Reset(cmd *cobra.Command) {
+	opt.Reset(cmd)
+}
+
+// setArgs sets os.Args and cmd's args to reflect args being
+// invoked as cmd, returning a func that restores os.Args.
+func setArgs(cmd *cobra.Command, args []string) func() {
+	prevArgs := os.Args
+	os.Args = append([]string{prevArgs[0]}, args...)
+	cmd.SetArgs(args)
+
+	return func() {
+		os.Args = prevArgs
+	}
+}
+
+// setEnv sets the given environment variables, returning a func
+// that restores the environment to how it was before.
+func setEnv(env map[string]string) func() {
+	prev := make(map[string]*string, len(env))
+	for k := range env {
+		if old, ok := os.LookupEnv(k); ok {
+			prev[k] = &old
+		} else {
+			prev[k] = nil
+		}
+	}
+
+	for k, v := range env {
+		os.Setenv(k, v)
+	}
+
+	return func() {
+		for k, old := range prev {
+			if old == nil {
+				os.Unsetenv(k)
+			} else {
+				os.Setenv(k, *old)
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package opt
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// Required marks an option as mandatory. If, once flags are parsed,
+// the option has neither a flag value, an environment variable
+// value, a config file value, nor a non-zero default, the command
+// fails before running with an error listing every missing
+// required option.
+func Required[T OptType]() OptFunc[T] {
+	return func(o *opt[T]) {
+		o.required = true
+	}
+}
+
+// requiredOpt is the non-generic view of an opt[T] that the
+// required-option registry needs.
+type requiredOpt interface {
+	requiredCheck() (missing bool, flagName, envName string)
+}
+
+var (
+	requiredMu    sync.Mutex
+	requiredOpts  = map[*cobra.Command][]requiredOpt{}
+	requiredHooks = map[*cobra.Command]bool{}
+)
+
+// registerRequired records o against cmd's required-option registry
+// when o is required, installing cmd's enforcement PersistentPreRunE
+// the first time this happens for cmd. Any PersistentPreRunE already
+// set on cmd is preserved and run first.
+func registerRequired[T OptType](cmd *cobra.Command, o *opt[T]) {
+	if !o.required {
+		return
+	}
+
+	requiredMu.Lock()
+	defer requiredMu.Unlock()
+
+	requiredOpts[cmd] = append(requiredOpts[cmd], o)
+
+	if requiredHooks[cmd] {
+		return
+	}
+	requiredHooks[cmd] = true
+
+	prev := cmd.PersistentPreRunE
+	cmd.PersistentPreRunE = func(c *cobra.Command, args []string) error {
+		if prev != nil {
+			if err := prev(c, args); err != nil {
+				return err
+			}
+		}
+		return checkRequired(cmd)
+	}
+}
+
+// checkRequired returns a combined error listing every required
+// option registered against cmd that was not set, or nil if all of
+// them were.
+func checkRequired(cmd *cobra.Command) error {
+	requiredMu.Lock()
+	opts := append([]requiredOpt(nil), requiredOpts[cmd]...)
+	requiredMu.Unlock()
+
+	var missing []string
+	for _, o := range opts {
+		isMissing, flagName, envName := o.requiredCheck()
+		if isMissing {
+			missing = append(missing, describeRequired(flagName, envName))
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	sort.Strings(missing)
+	return fmt.Errorf("required options not set: %s", strings.Join(missing, ", "))
+}
+
+func describeRequired(flagName, envName string) string {
+	switch {
+	case flagName != "" && envName != "":
+		return fmt.Sprintf("--%s (%s)", flagName, envName)
+	case flagName != "":
+		return fmt.Sprintf("--%s", flagName)
+	case envName != "":
+		return envName
+	default:
+		return "(unnamed option)"
+	}
+}
+
+// requiredCheck reports whether o is required but unset, along with
+// its flag and env names for use in the combined error message.
+func (o *opt[T]) requiredCheck() (bool, string, string) {
+	if o.flagName != "" && o.flag != nil && o.flag.Changed {
+		return false, o.flagName, o.envName
+	}
+
+	if o.envName != "" {
+		if _, ok := os.LookupEnv(o.envName); ok {
+			return false, o.flagName, o.envName
+		}
+	}
+
+	if _, ok := configValue(o.cmd, configKeyFor(o.cmd, o.name, o.configKey)); ok {
+		return false, o.flagName, o.envName
+	}
+
+	if !reflect.ValueOf(o.defaultValue).IsZero() {
+		return false, o.flagName, o.envName
+	}
+
+	return true, o.flagName, o.envName
+}
@@ -0,0 +1,81 @@
+package opt
+
+import (
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// resettableOpt is the non-generic view of an opt[T] that test
+// support needs in order to let a command be executed more than
+// once.
+type resettableOpt interface {
+	reset()
+}
+
+var (
+	resettableMu   sync.Mutex
+	resettableOpts = map[*cobra.Command][]resettableOpt{}
+)
+
+// registerResettable records o so it can later be re-armed by
+// [Reset].
+func registerResettable[T OptType](cmd *cobra.Command, o *opt[T]) {
+	resettableMu.Lock()
+	defer resettableMu.Unlock()
+
+	resettableOpts[cmd] = append(resettableOpts[cmd], o)
+}
+
+// Reset undoes this package's bookkeeping from a previous
+// cmd.Execute: it re-arms every option registered against cmd so
+// its value will be re-resolved (from scratch) the next time cmd
+// runs, resets each option's flag to its unset, default state, and
+// clears any aggregated [Validate] errors from the previous run.
+//
+// Reset does not touch the process environment; see the opttest
+// package for a test helper that handles that too, so the same
+// *cobra.Command can be exercised repeatedly in table-driven tests.
+func Reset(cmd *cobra.Command) {
+	resettableMu.Lock()
+	opts := append([]resettableOpt(nil), resettableOpts[cmd]...)
+	resettableMu.Unlock()
+
+	for _, o := range opts {
+		o.reset()
+	}
+
+	errsMu.Lock()
+	delete(errs, cmd)
+	errsMu.Unlock()
+}
+
+func (o *opt[T]) reset() {
+	o.once = sync.Once{}
+	o.resetFlag()
+}
+
+// resetFlag restores o's flag to its unset, default state.
+//
+// It deliberately does not reuse o.flag.Value.Set(o.flag.DefValue):
+// for slice/map-backed flags (see e.g. [StringSlice]), Set merges
+// into the existing value rather than replacing it once the flag's
+// internal "changed" bit has been tripped, which Set itself would
+// trip again - so a reset that way would still leak into the next
+// real parse. Instead, o.flagCreateFunc is replayed against a
+// throwaway FlagSet to build a fresh, never-Set Value of the same
+// type, which is swapped onto o.flag in place of the stale one.
+func (o *opt[T]) resetFlag() {
+	if o.flagName == "" || o.flag == nil {
+		return
+	}
+
+	fresh := pflag.NewFlagSet("", pflag.ContinueOnError)
+	o.flagCreateFunc(fresh, o)
+
+	freshFlag := fresh.Lookup(o.flagName)
+	o.flag.Value = freshFlag.Value
+	o.flag.DefValue = freshFlag.DefValue
+	o.flag.Changed = false
+}
@@ -0,0 +1,104 @@
+package opt
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// Source identifies which layer of the precedence chain supplied an
+// option's final value.
+type Source int
+
+const (
+	// SourceDefault means the option's default value was used.
+	SourceDefault Source = iota
+	// SourceConfig means the value came from a config file loaded
+	// with [LoadConfig].
+	SourceConfig
+	// SourceEnv means the value came from an environment variable.
+	SourceEnv
+	// SourceFlag means the value came from a command-line flag.
+	SourceFlag
+)
+
+// String returns a lower-case name for s, e.g. "flag".
+func (s Source) String() string {
+	switch s {
+	case SourceDefault:
+		return "default"
+	case SourceConfig:
+		return "config"
+	case SourceEnv:
+		return "env"
+	case SourceFlag:
+		return "flag"
+	default:
+		return "unknown"
+	}
+}
+
+// sourcedOpt is the non-generic view of an opt[T] that source
+// tracking needs.
+type sourcedOpt interface {
+	optName() string
+	sourceValue() (Source, string)
+}
+
+var (
+	sourcedMu   sync.Mutex
+	sourcedOpts = map[*cobra.Command][]sourcedOpt{}
+)
+
+// registerSourced records o so its resolved source can later be
+// reported by [SourceOf] or [DebugSources].
+func registerSourced[T OptType](cmd *cobra.Command, o *opt[T]) {
+	sourcedMu.Lock()
+	defer sourcedMu.Unlock()
+
+	sourcedOpts[cmd] = append(sourcedOpts[cmd], o)
+}
+
+// SourceOf reports which layer supplied the final value of the
+// option named name on cmd. It must be called after cmd has
+// initialized (i.e. from or after a Run function), since that is
+// when the source is resolved.
+func SourceOf(cmd *cobra.Command, name string) Source {
+	sourcedMu.Lock()
+	defer sourcedMu.Unlock()
+
+	for _, o := range sourcedOpts[cmd] {
+		if o.optName() == name {
+			source, _ := o.sourceValue()
+			return source
+		}
+	}
+
+	return SourceDefault
+}
+
+// DebugSources writes, for every option registered against any
+// command, its final value and which layer supplied it. It is
+// intended for diagnosing precedence bugs (e.g. an environment
+// variable unexpectedly overriding a flag) without a debugger.
+func DebugSources(w io.Writer) {
+	sourcedMu.Lock()
+	defer sourcedMu.Unlock()
+
+	for cmd, opts := range sourcedOpts {
+		for _, o := range opts {
+			source, value := o.sourceValue()
+			fmt.Fprintf(w, "%s: %s=%q (%s)\n", cmd.CommandPath(), o.optName(), value, source)
+		}
+	}
+}
+
+func (o *opt[T]) optName() string {
+	return o.name
+}
+
+func (o *opt[T]) sourceValue() (Source, string) {
+	return o.source, fmt.Sprint(*o.v)
+}
@@ -0,0 +1,138 @@
+package opt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Int sets up an int option with the specified name
+func Int(cmd *cobra.Command, v *int, name string, optFuncs ...OptFunc[int]) {
+	doVar(
+		cmd,
+		v,
+		name,
+		optFuncs,
+		strconv.Atoi,
+		func(flagSet *pflag.FlagSet, o *opt[int]) {
+			flagSet.IntVarP(&o.flagV, o.flagName, o.flagShorthand, o.defaultValue, o.usage)
+		},
+	)
+}
+
+// Int64 sets up an int64 option with the specified name
+func Int64(cmd *cobra.Command, v *int64, name string, optFuncs ...OptFunc[int64]) {
+	doVar(
+		cmd,
+		v,
+		name,
+		optFuncs,
+		func(in string) (int64, error) { return strconv.ParseInt(in, 10, 64) },
+		func(flagSet *pflag.FlagSet, o *opt[int64]) {
+			flagSet.Int64VarP(&o.flagV, o.flagName, o.flagShorthand, o.defaultValue, o.usage)
+		},
+	)
+}
+
+// Uint sets up a uint option with the specified name
+func Uint(cmd *cobra.Command, v *uint, name string, optFuncs ...OptFunc[uint]) {
+	doVar(
+		cmd,
+		v,
+		name,
+		optFuncs,
+		func(in string) (uint, error) {
+			parsed, err := strconv.ParseUint(in, 10, strconv.IntSize)
+			return uint(parsed), err
+		},
+		func(flagSet *pflag.FlagSet, o *opt[uint]) {
+			flagSet.UintVarP(&o.flagV, o.flagName, o.flagShorthand, o.defaultValue, o.usage)
+		},
+	)
+}
+
+// Float64 sets up a float64 option with the specified name
+func Float64(cmd *cobra.Command, v *float64, name string, optFuncs ...OptFunc[float64]) {
+	doVar(
+		cmd,
+		v,
+		name,
+		optFuncs,
+		func(in string) (float64, error) { return strconv.ParseFloat(in, 64) },
+		func(flagSet *pflag.FlagSet, o *opt[float64]) {
+			flagSet.Float64VarP(&o.flagV, o.flagName, o.flagShorthand, o.defaultValue, o.usage)
+		},
+	)
+}
+
+// Duration sets up a [time.Duration] option with the specified name
+func Duration(cmd *cobra.Command, v *time.Duration, name string, optFuncs ...OptFunc[time.Duration]) {
+	doVar(
+		cmd,
+		v,
+		name,
+		optFuncs,
+		time.ParseDuration,
+		func(flagSet *pflag.FlagSet, o *opt[time.Duration]) {
+			flagSet.DurationVarP(&o.flagV, o.flagName, o.flagShorthand, o.defaultValue, o.usage)
+		},
+	)
+}
+
+// StringSlice sets up a comma-separated []string option with the
+// specified name.
+func StringSlice(cmd *cobra.Command, v *[]string, name string, optFuncs ...OptFunc[[]string]) {
+	doVar(
+		cmd,
+		v,
+		name,
+		optFuncs,
+		func(in string) ([]string, error) {
+			if in == "" {
+				return nil, nil
+			}
+			return strings.Split(in, ","), nil
+		},
+		func(flagSet *pflag.FlagSet, o *opt[[]string]) {
+			flagSet.StringSliceVarP(&o.flagV, o.flagName, o.flagShorthand, o.defaultValue, o.usage)
+		},
+	)
+}
+
+// StringToStringMap sets up a map[string]string option with the
+// specified name. Its string form (for environment variables and
+// config file values) is a comma-separated list of "key=value"
+// pairs, matching [pflag]'s StringToString flag syntax.
+func StringToStringMap(cmd *cobra.Command, v *map[string]string, name string, optFuncs ...OptFunc[map[string]string]) {
+	doVar(
+		cmd,
+		v,
+		name,
+		optFuncs,
+		parseStringToStringMap,
+		func(flagSet *pflag.FlagSet, o *opt[map[string]string]) {
+			flagSet.StringToStringVarP(&o.flagV, o.flagName, o.flagShorthand, o.defaultValue, o.usage)
+		},
+	)
+}
+
+func parseStringToStringMap(in string) (map[string]string, error) {
+	out := map[string]string{}
+	if in == "" {
+		return out, nil
+	}
+
+	for _, pair := range strings.Split(in, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("opt: invalid key=value pair %q", pair)
+		}
+		out[k] = v
+	}
+
+	return out, nil
+}